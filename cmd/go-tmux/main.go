@@ -0,0 +1,98 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+// Command go-tmux starts, stops or attaches tmux sessions described by a
+// YAML or TOML configuration file (see the config package for the schema).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	tmux "github.com/yorick1989/go-tmux"
+	"github.com/yorick1989/go-tmux/config"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: %s -f <config.yml> {start|stop|attach}\n", os.Args[0])
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	file := flag.String("f", "", "path to the session config file (.yml, .yaml or .toml)")
+	flag.Parse()
+
+	if *file == "" || flag.NArg() != 1 {
+		usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load(*file)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "go-tmux:", err)
+		os.Exit(1)
+	}
+
+	server := tmux.NewServer()
+
+	if err := run(server, cfg, flag.Arg(0)); err != nil {
+		fmt.Fprintln(os.Stderr, "go-tmux:", err)
+		os.Exit(1)
+	}
+}
+
+func run(server *tmux.Server, cfg *config.Config, action string) error {
+	switch action {
+	case "start":
+		return start(server, cfg)
+	case "stop":
+		_, _, err := tmux.RunCmd([]string{"kill-session", "-t", cfg.Name})
+		return err
+	case "attach":
+		_, _, err := tmux.RunCmd([]string{"attach-session", "-t", cfg.Name})
+		return err
+	default:
+		return fmt.Errorf("unknown action %q (want start, stop or attach)", action)
+	}
+}
+
+func start(server *tmux.Server, cfg *config.Config) error {
+	tmuxCfg, err := cfg.Build(server)
+	if err != nil {
+		return err
+	}
+
+	if err := tmuxCfg.Apply(); err != nil {
+		return err
+	}
+
+	// spec.ShellCommandBefore already ran inside Apply, against the lone
+	// initial pane, before it was split into the rest of the window's
+	// panes (tmux.Window.PreSplitCommand).
+	session := tmuxCfg.Sessions[0]
+	for wi, w := range session.Windows {
+		spec := cfg.Windows[wi]
+
+		for pi, pane := range w.Panes {
+			if pi >= len(spec.Panes) {
+				break
+			}
+			for _, command := range spec.Panes[pi].Commands {
+				if err := pane.RunCommand(command); err != nil {
+					return err
+				}
+			}
+		}
+
+		if spec.Sync {
+			args := []string{"setw", "-t", w.Target(), "synchronize-panes", "on"}
+			if _, _, err := tmux.RunCmd(args); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+// Package config parses declarative session specifications (YAML or TOML)
+// into a tmux.Configuration that can be applied with Configuration.Apply.
+// The schema mirrors what tools like tmuxinator/smug accept, so existing
+// session files are mostly compatible.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	tmux "github.com/yorick1989/go-tmux"
+)
+
+// Pane is the declarative spec for a single tmux.Pane.
+type Pane struct {
+	Dir      string   `yaml:"dir" toml:"dir"`
+	Split    string   `yaml:"split" toml:"split"` // "horizontal" or "vertical"
+	Size     string   `yaml:"size" toml:"size"`   // e.g. "50%" or "10" (lines/columns)
+	Zoom     bool     `yaml:"zoom" toml:"zoom"`
+	Commands []string `yaml:"commands" toml:"commands"`
+}
+
+// Window is the declarative spec for a single tmux.Window.
+type Window struct {
+	Name               string `yaml:"name" toml:"name"`
+	Dir                string `yaml:"dir" toml:"dir"`
+	Layout             string `yaml:"layout" toml:"layout"`
+	Sync               bool   `yaml:"sync" toml:"sync"`
+	ShellCommandBefore string `yaml:"shell_command_before" toml:"shell_command_before"`
+	Panes              []Pane `yaml:"panes" toml:"panes"`
+}
+
+// Config is the root of a session specification file.
+type Config struct {
+	Name    string   `yaml:"name" toml:"name"`
+	Root    string   `yaml:"root" toml:"root"`
+	Attach  bool     `yaml:"attach" toml:"attach"`
+	Windows []Window `yaml:"windows" toml:"windows"`
+}
+
+// knownLayouts mirrors the Layout* constants exported by the tmux package.
+var knownLayouts = map[string]bool{
+	tmux.LayoutEvenHorizontal: true,
+	tmux.LayoutEvenVertical:   true,
+	tmux.LayoutMainHorizontal: true,
+	tmux.LayoutMainVertical:   true,
+	tmux.LayoutTiled:          true,
+}
+
+// Load reads a session spec from path. The format (YAML or TOML) is
+// determined from the file extension: .yml/.yaml or .toml.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yml", ".yaml":
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	case ".toml":
+		if _, err := toml.Decode(string(data), cfg); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config extension %q (want .yml, .yaml or .toml)", ext)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Validate checks the spec for the mistakes Apply can't recover from:
+// unknown layouts, sessions without windows, and pane directories that
+// escape the session root.
+func (c *Config) Validate() error {
+	if len(c.Windows) == 0 {
+		return fmt.Errorf("session %q doesn't define any windows", c.Name)
+	}
+
+	for _, w := range c.Windows {
+		if len(w.Layout) != 0 && !knownLayouts[w.Layout] {
+			return fmt.Errorf("window %q: unknown layout %q", w.Name, w.Layout)
+		}
+
+		for _, p := range w.Panes {
+			switch p.Split {
+			case "", "horizontal", "vertical":
+			default:
+				return fmt.Errorf("window %q: unknown split %q (want \"horizontal\" or \"vertical\")", w.Name, p.Split)
+			}
+
+			if err := checkPaneDir(c.Root, p.Dir); err != nil {
+				return fmt.Errorf("window %q: %w", w.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkPaneDir rejects relative pane directories that don't resolve under
+// root, since those would silently `cd` somewhere the user didn't intend.
+func checkPaneDir(root, dir string) error {
+	if dir == "" || filepath.IsAbs(dir) {
+		return nil
+	}
+
+	if root == "" {
+		return fmt.Errorf("pane dir %q is relative but session has no root", dir)
+	}
+
+	resolved := filepath.Join(root, dir)
+	if !strings.HasPrefix(resolved, filepath.Clean(root)+string(os.PathSeparator)) && resolved != filepath.Clean(root) {
+		return fmt.Errorf("pane dir %q escapes session root %q", dir, root)
+	}
+
+	return nil
+}
+
+// Build converts the spec into a tmux.Configuration bound to server. The
+// returned Configuration has not been applied yet; call Apply to start it.
+func (c *Config) Build(server *tmux.Server) (*tmux.Configuration, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	if server == nil {
+		return nil, errors.New("server was not initialized")
+	}
+
+	session := &tmux.Session{
+		Name:           c.Name,
+		StartDirectory: c.Root,
+	}
+
+	for _, w := range c.Windows {
+		window := tmux.Window{
+			Name:            w.Name,
+			StartDirectory:  w.Dir,
+			Layout:          w.Layout,
+			PreSplitCommand: w.ShellCommandBefore,
+		}
+
+		for _, p := range w.Panes {
+			window.AddPane(tmux.Pane{
+				Split: p.Split,
+				Size:  p.Size,
+				Zoom:  p.Zoom,
+				Dir:   p.Dir,
+			})
+		}
+
+		session.Windows = append(session.Windows, window)
+	}
+
+	cfg := &tmux.Configuration{
+		Server:   server,
+		Sessions: []*tmux.Session{session},
+	}
+
+	if c.Attach {
+		cfg.ActiveSession = session
+	}
+
+	return cfg, nil
+}
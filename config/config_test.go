@@ -0,0 +1,102 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package config
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{
+			name:    "rejects empty window list",
+			cfg:     Config{Name: "s"},
+			wantErr: true,
+		},
+		{
+			name: "rejects unknown layout",
+			cfg: Config{
+				Name:    "s",
+				Windows: []Window{{Name: "w", Layout: "not-a-real-layout"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "accepts a known layout",
+			cfg: Config{
+				Name:    "s",
+				Windows: []Window{{Name: "w", Layout: "tiled"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "rejects unknown split",
+			cfg: Config{
+				Name: "s",
+				Windows: []Window{{
+					Name:  "w",
+					Panes: []Pane{{Split: "diagonal"}},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects relative pane dir without a session root",
+			cfg: Config{
+				Name: "s",
+				Windows: []Window{{
+					Name:  "w",
+					Panes: []Pane{{Dir: "logs"}},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "rejects pane dir that escapes the session root",
+			cfg: Config{
+				Name: "s",
+				Root: "/home/user/project",
+				Windows: []Window{{
+					Name:  "w",
+					Panes: []Pane{{Dir: "../../etc"}},
+				}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "accepts a pane dir that resolves under the session root",
+			cfg: Config{
+				Name: "s",
+				Root: "/home/user/project",
+				Windows: []Window{{
+					Name:  "w",
+					Panes: []Pane{{Dir: "logs"}},
+				}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "accepts an absolute pane dir regardless of root",
+			cfg: Config{
+				Name: "s",
+				Windows: []Window{{
+					Name:  "w",
+					Panes: []Pane{{Dir: "/var/log"}},
+				}},
+			},
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
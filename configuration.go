@@ -9,6 +9,8 @@ package tmux
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 type Configuration struct {
@@ -108,21 +110,43 @@ func (c *Configuration) Apply() error {
 			// Setup panes for created window
 			orig_panes := w.Panes
 			w.Panes, _ = w.ListPanes()
-			for idx := range orig_panes {
+
+			// Run once in the lone initial pane, before it's split into
+			// the rest of orig_panes.
+			if len(w.PreSplitCommand) != 0 && len(w.Panes) > 0 {
+				if err := w.Panes[0].RunCommand(w.PreSplitCommand); err != nil {
+					return err
+				}
+			}
+
+			for idx, orig_pane := range orig_panes {
 				// First pane is created automatically, so split existing window
 				if idx > 0 {
 					// Create a new pane
-					pane, err := w.SplitPane()
+					pane, err := w.SplitPane(splitPaneOpts(orig_pane))
 					if err != nil {
 						return err
 					}
 					w.Panes[idx] = pane
 				}
+
+				if len(orig_pane.Dir) != 0 {
+					if err := w.Panes[idx].RunCommand(fmt.Sprintf("cd %s", ShellQuote(orig_pane.Dir))); err != nil {
+						return err
+					}
+				}
+
+				if orig_pane.Zoom {
+					args := []string{"resize-pane", "-Z", "-t", w.Panes[idx].Target()}
+					if _, _, err_exec := RunCmd(args); err_exec != nil {
+						return err_exec
+					}
+				}
 			}
 
 			// Select layout if defined
 			if len(w.Layout) != 0 {
-				args := []string{"select-layout", "-t", fmt.Sprintf("%v", w.Id), w.Layout}
+				args := []string{"select-layout", "-t", w.Target(), w.Layout}
 				_, _, err_exec := RunCmd(args)
 				if err_exec != nil {
 					return err_exec
@@ -138,3 +162,34 @@ func (c *Configuration) Apply() error {
 
 	return nil
 }
+
+// ShellQuote escapes s for safe use as a single word in a POSIX shell
+// command line, e.g. when building a "cd <dir>" string that's sent through
+// send-keys. Unlike Go's %q (string-literal quoting), this is the real
+// shell quoting rule: wrap s in single quotes, closing and reopening the
+// quote around each embedded single quote, so the shell does no further
+// expansion of its contents -- no command substitution, backticks or
+// variable expansion.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// splitPaneOpts translates a Pane's declarative Split/Size fields into the
+// SplitPaneOpts used to create it.
+func splitPaneOpts(p Pane) *SplitPaneOpts {
+	opts := &SplitPaneOpts{Horizontal: p.Split == "horizontal"}
+
+	if len(p.Size) == 0 {
+		return opts
+	}
+
+	if strings.HasSuffix(p.Size, "%") {
+		if percent, err := strconv.Atoi(strings.TrimSuffix(p.Size, "%")); err == nil {
+			opts.Percent = percent
+		}
+	} else if size, err := strconv.Atoi(p.Size); err == nil {
+		opts.Size = size
+	}
+
+	return opts
+}
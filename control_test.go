@@ -0,0 +1,144 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseNotification(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		wantName string
+		wantArgs []string
+	}{
+		{
+			name:     "no arguments",
+			line:     "%exit",
+			wantName: "exit",
+			wantArgs: nil,
+		},
+		{
+			name:     "single argument",
+			line:     "%window-add @3",
+			wantName: "window-add",
+			wantArgs: []string{"@3"},
+		},
+		{
+			name:     "multiple arguments",
+			line:     "%output %5 hello world",
+			wantName: "output",
+			wantArgs: []string{"%5", "hello", "world"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotName, gotArgs := parseNotification(tt.line)
+			if gotName != tt.wantName {
+				t.Errorf("parseNotification() name = %q, want %q", gotName, tt.wantName)
+			}
+			if !reflect.DeepEqual(gotArgs, tt.wantArgs) {
+				t.Errorf("parseNotification() args = %v, want %v", gotArgs, tt.wantArgs)
+			}
+		})
+	}
+}
+
+// TestReadLoopBlockCorrelation feeds readLoop a synthetic control-mode
+// stream directly (no real tmux process) and checks that %begin/%end/%error
+// blocks become correlated controlReplies, while everything else is
+// dispatched as a Notification.
+func TestReadLoopBlockCorrelation(t *testing.T) {
+	input := strings.Join([]string{
+		"%window-add @9",
+		"%begin 1 1 0",
+		"hello",
+		"world",
+		"%end 1 1 0",
+		"%session-changed $1 name",
+		"%begin 2 2 0",
+		"boom",
+		"%error 2 2 0",
+		"",
+	}, "\n")
+
+	c := &ControlClient{
+		notify:  make(chan Notification, 8),
+		pending: make(chan controlReply, 8),
+		done:    make(chan struct{}),
+	}
+
+	c.readLoop(strings.NewReader(input))
+
+	reply1, ok := <-c.pending
+	if !ok {
+		t.Fatal("expected a reply for the first %begin/%end block")
+	}
+	if reply1.err != nil {
+		t.Errorf("first reply err = %v, want nil", reply1.err)
+	}
+	if reply1.out != "hello\nworld" {
+		t.Errorf("first reply out = %q, want %q", reply1.out, "hello\nworld")
+	}
+
+	reply2, ok := <-c.pending
+	if !ok {
+		t.Fatal("expected a reply for the second %begin/%error block")
+	}
+	if reply2.err == nil {
+		t.Error("second reply err = nil, want an error for %error")
+	}
+	if reply2.out != "boom" {
+		t.Errorf("second reply out = %q, want %q", reply2.out, "boom")
+	}
+
+	if _, ok := <-c.pending; ok {
+		t.Error("pending should be closed once readLoop returns")
+	}
+
+	n1 := <-c.notify
+	if n1.Name != "window-add" || !reflect.DeepEqual(n1.Args, []string{"@9"}) {
+		t.Errorf("first notification = %+v, want Name=window-add Args=[@9]", n1)
+	}
+
+	n2 := <-c.notify
+	if n2.Name != "session-changed" || !reflect.DeepEqual(n2.Args, []string{"$1", "name"}) {
+		t.Errorf("second notification = %+v, want Name=session-changed Args=[$1 name]", n2)
+	}
+
+	if _, ok := <-c.notify; ok {
+		t.Error("notify should be closed once readLoop returns")
+	}
+
+	select {
+	case <-c.done:
+	default:
+		t.Error("done should be closed once readLoop returns")
+	}
+}
+
+func TestDeliverDropsOldestWhenFull(t *testing.T) {
+	c := &ControlClient{notify: make(chan Notification, 2)}
+
+	c.deliver(Notification{Name: "a"})
+	c.deliver(Notification{Name: "b"})
+	c.deliver(Notification{Name: "c"}) // notify is full; "a" should be dropped
+
+	first := <-c.notify
+	second := <-c.notify
+
+	if first.Name != "b" || second.Name != "c" {
+		t.Errorf("got %q, %q; want \"b\", \"c\"", first.Name, second.Name)
+	}
+
+	select {
+	case n := <-c.notify:
+		t.Errorf("unexpected third notification: %+v", n)
+	default:
+	}
+}
@@ -21,6 +21,14 @@ type Pane struct {
 	WindowIndex int
 	Active      bool
 	Index       int
+
+	// The fields below are declarative configuration, honoured by
+	// Configuration.Apply when this pane is created. They're left zero
+	// on panes returned by ListPanes/ListWindows.
+	Split string // "horizontal" or "vertical"; empty defaults to "vertical"
+	Size  string // e.g. "50%" (-p) or "10" (-l), in lines/columns
+	Zoom  bool   // zoom this pane with resize-pane -Z once it's created
+	Dir   string // `cd` the pane here once it's created
 }
 
 // Creates a new pane object.
@@ -136,7 +144,7 @@ func (p *Pane) GetCurrentPath() (string, error) {
 func (p *Pane) Pipe(path string) (string, error) {
 	args := []string{
 		"pipe-pane",
-		"-t", fmt.Sprintf("%s:%s.%d", p.SessionName, p.WindowName, p.Index),
+		"-t", p.Target(),
 		"-o", fmt.Sprintf("cat >>%s", path),
 	}
 
@@ -152,7 +160,7 @@ func (p *Pane) Pipe(path string) (string, error) {
 func (p *Pane) Capture() (string, error) {
 	args := []string{
 		"capture-pane",
-		"-t", fmt.Sprintf("%s:%s.%d", p.SessionName, p.WindowName, p.Index),
+		"-t", p.Target(),
 		"-p",
 	}
 
@@ -171,7 +179,7 @@ func (p *Pane) Capture() (string, error) {
 func (p *Pane) RunCommand(command string) error {
 	args := []string{
 		"send-keys",
-		"-t", fmt.Sprintf("%s:%s.%d", p.SessionName, p.WindowName, p.Index),
+		"-t", p.Target(),
 		command,
 		"C-m",
 	}
@@ -186,7 +194,7 @@ func (p *Pane) RunCommand(command string) error {
 func (p *Pane) Select() error {
 	args := []string{
 		"select-pane",
-		"-t", fmt.Sprintf("%s:%s.%d", p.SessionName, p.WindowName, p.Index),
+		"-t", p.Target(),
 	}
 	_, stdErr, err := RunCmd(args)
 	if err != nil {
@@ -0,0 +1,131 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Result is the outcome of a command run with RunCommandAsync.
+type Result struct {
+	ExitCode int
+	Output   string
+	Err      error
+}
+
+// RunCommandSync runs command in the pane and blocks until it finishes (or
+// timeout elapses), using `wait-for` to detect completion since send-keys
+// alone gives no way to know when - or whether - a command finished. It
+// returns the command's exit code and its captured stdout/stderr.
+func (p *Pane) RunCommandSync(command string, timeout time.Duration) (exitCode int, output string, err error) {
+	result := <-p.runCommandAsync(command, timeout)
+	return result.ExitCode, result.Output, result.Err
+}
+
+// RunCommandAsync runs command in the pane without blocking, and returns a
+// channel that receives its Result once `wait-for` reports completion (or
+// timeout elapses).
+func (p *Pane) RunCommandAsync(command string, timeout time.Duration) <-chan Result {
+	return p.runCommandAsync(command, timeout)
+}
+
+func (p *Pane) runCommandAsync(command string, timeout time.Duration) <-chan Result {
+	results := make(chan Result, 1)
+	channel := fmt.Sprintf("go-tmux-%d-%d", p.ID, time.Now().UnixNano())
+	outFile := filepath.Join(os.TempDir(), channel+".out")
+
+	// Redirect the command's own stdout/stderr to a plain file and read it
+	// back directly instead of going through a tmux buffer: filling one with
+	// `set-buffer` from the pane's shell would mean passing arbitrary
+	// command output as a command-line argument, re-escaped into shell
+	// syntax -- the same class of bug as feeding captured text through
+	// send-keys.
+	wrapped := fmt.Sprintf(
+		`{ %s; } >%s 2>&1; tmux set-buffer -b %s "$?"; tmux wait-for -S %s-done`,
+		command, outFile, channel, channel,
+	)
+
+	if err := p.RunCommand(wrapped); err != nil {
+		results <- Result{Err: err}
+		return results
+	}
+
+	go func() {
+		defer os.Remove(outFile)
+
+		// Run `wait-for` as a cancelable subprocess, not through RunCmd:
+		// if the pane's command never finishes, a plain blocking wait-for
+		// would leak this goroutine and its tmux process forever once
+		// timeout fires below. Canceling ctx kills the process instead.
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		err := exec.CommandContext(ctx, "tmux", "wait-for", channel+"-done").Run()
+		if ctx.Err() == context.DeadlineExceeded {
+			results <- Result{Err: fmt.Errorf("timed out waiting for command to finish in pane %s", p.Target())}
+			return
+		}
+		if err != nil {
+			results <- Result{Err: err}
+			return
+		}
+
+		status, stdErr, err := RunCmd([]string{"show-buffer", "-b", channel})
+		if err != nil {
+			results <- Result{Err: fmt.Errorf("%v: %s", err, stdErr)}
+			return
+		}
+		_, _, _ = RunCmd([]string{"delete-buffer", "-b", channel})
+
+		exitCode, err := parseExitCode(status)
+		if err != nil {
+			results <- Result{Err: fmt.Errorf("parsing exit code %q: %w", status, err)}
+			return
+		}
+
+		output, err := os.ReadFile(outFile)
+		if err != nil {
+			results <- Result{Err: err}
+			return
+		}
+
+		results <- Result{ExitCode: exitCode, Output: string(output)}
+	}()
+
+	return results
+}
+
+// parseExitCode parses the "$?" value tmux's show-buffer returns, which
+// may carry a trailing newline.
+func parseExitCode(buf string) (int, error) {
+	return strconv.Atoi(strings.TrimSpace(buf))
+}
+
+// RunAll runs one command per pane in w, in order, and waits for all of
+// them to finish. It returns one Result per pane, in the same order as
+// w.Panes.
+func (w *Window) RunAll(commands []string, timeout time.Duration) ([]Result, error) {
+	if len(commands) != len(w.Panes) {
+		return nil, fmt.Errorf("RunAll: got %d commands for %d panes", len(commands), len(w.Panes))
+	}
+
+	channels := make([]<-chan Result, len(w.Panes))
+	for i := range w.Panes {
+		channels[i] = w.Panes[i].RunCommandAsync(commands[i], timeout)
+	}
+
+	results := make([]Result, len(channels))
+	for i, ch := range channels {
+		results[i] = <-ch
+	}
+
+	return results, nil
+}
@@ -0,0 +1,33 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import "testing"
+
+func TestParseExitCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		buf     string
+		want    int
+		wantErr bool
+	}{
+		{name: "plain digits", buf: "0", want: 0},
+		{name: "non-zero", buf: "127", want: 127},
+		{name: "trailing newline from show-buffer", buf: "1\n", want: 1},
+		{name: "surrounding whitespace", buf: "  2  ", want: 2},
+		{name: "non-numeric is an error", buf: "not a number", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseExitCode(tt.buf)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseExitCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("parseExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package snapshot
+
+import (
+	"fmt"
+	"path/filepath"
+
+	tmux "github.com/yorick1989/go-tmux"
+)
+
+// RestoreOptions controls how a Snapshot is rebuilt on the server.
+type RestoreOptions struct {
+	// Override kills any existing session with a colliding name before
+	// rebuilding it.
+	Override bool
+	// Attach attaches to the last restored session once it's rebuilt.
+	Attach bool
+	// RunCommands re-runs each pane's recorded current command after
+	// restoring its working directory.
+	RunCommands bool
+}
+
+// Restore rebuilds every session captured in a Snapshot previously saved to
+// dir: it recreates sessions/windows/panes, re-applies the saved window
+// layout, `cd`s each pane to its saved path, optionally re-runs its
+// recorded command, and replays the captured scrollback into the pane.
+func Restore(server *tmux.Server, dir string, opts RestoreOptions) error {
+	snap, err := Load(dir)
+	if err != nil {
+		return err
+	}
+
+	var last *tmux.Session
+	for _, s := range snap.Sessions {
+		session, err := restoreSession(server, dir, s, opts)
+		if err != nil {
+			return fmt.Errorf("session %s: %w", s.Name, err)
+		}
+		last = session
+	}
+
+	if opts.Attach && last != nil {
+		return last.AttachSession()
+	}
+
+	return nil
+}
+
+func restoreSession(server *tmux.Server, dir string, s Session, opts RestoreOptions) (*tmux.Session, error) {
+	if opts.Override {
+		_, _, _ = tmux.RunCmd([]string{"kill-session", "-t", s.Name})
+	}
+
+	if len(s.Windows) == 0 {
+		return nil, fmt.Errorf("no windows captured")
+	}
+
+	first := s.Windows[0]
+	session, err := server.NewSession(s.Name, "-n", first.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	for wi, w := range s.Windows {
+		var window *tmux.Window
+		if wi == 0 {
+			windows, err := session.ListWindows()
+			if err != nil {
+				return nil, err
+			}
+			window = &windows[0]
+		} else {
+			window, err = session.NewWindow(w.Name)
+			if err != nil {
+				return nil, fmt.Errorf("window %s: %w", w.Name, err)
+			}
+		}
+
+		if err := restoreWindow(dir, s.Name, window, w, opts); err != nil {
+			return nil, fmt.Errorf("window %s: %w", w.Name, err)
+		}
+	}
+
+	return session, nil
+}
+
+func restoreWindow(dir, sessionName string, window *tmux.Window, w Window, opts RestoreOptions) error {
+	// Create the remaining panes; the first one already exists.
+	for i := 1; i < len(w.Panes); i++ {
+		if _, err := window.SplitPane(nil); err != nil {
+			return err
+		}
+	}
+
+	panes, err := window.ListPanes()
+	if err != nil {
+		return err
+	}
+
+	for i, p := range w.Panes {
+		if i >= len(panes) {
+			break
+		}
+		if err := restorePane(dir, panes[i], p, opts); err != nil {
+			return fmt.Errorf("pane %d: %w", p.Index, err)
+		}
+	}
+
+	if w.Layout != "" {
+		args := []string{"select-layout", "-t", fmt.Sprintf("@%d", window.Id), w.Layout}
+		if _, _, err := tmux.RunCmd(args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func restorePane(dir string, pane tmux.Pane, p Pane, opts RestoreOptions) error {
+	if p.CurrentPath != "" {
+		if err := pane.RunCommand(fmt.Sprintf("cd %s", tmux.ShellQuote(p.CurrentPath))); err != nil {
+			return err
+		}
+	}
+
+	if opts.RunCommands && p.CurrentCommand != "" {
+		if err := pane.RunCommand(p.CurrentCommand); err != nil {
+			return err
+		}
+	}
+
+	if p.BufferFile == "" {
+		return nil
+	}
+
+	return replayBuffer(dir, p.BufferFile, pane)
+}
+
+// replayBuffer redisplays the captured scrollback in pane by having the
+// pane's shell `cat` the saved buffer file. The text itself is never sent
+// to the pane as input (neither with send-keys nor with paste-buffer, which
+// inserts a buffer as typed input and turns each newline into Enter): that
+// would feed old terminal output to a live shell, and any `$( ... )` or
+// backtick sequence that happens to appear in it (e.g. from a previously
+// echoed command) would be executed during restore instead of just
+// redisplayed.
+func replayBuffer(dir, bufferFile string, pane tmux.Pane) error {
+	path := filepath.Join(dir, bufferFile)
+	return pane.RunCommand(fmt.Sprintf("cat %s", tmux.ShellQuote(path)))
+}
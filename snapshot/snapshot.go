@@ -0,0 +1,219 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+// Package snapshot captures the full state of a running tmux server
+// (sessions, windows, pane layouts, working directories and scrollback)
+// to a directory tree, and rebuilds it later with Restore.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tmux "github.com/yorick1989/go-tmux"
+)
+
+// DefaultHistoryLines is the number of scrollback lines captured from each
+// pane when Capture is called without an explicit limit.
+const DefaultHistoryLines = 2000
+
+const metadataFile = "snapshot.json"
+
+// Pane is the captured state of a single tmux.Pane.
+type Pane struct {
+	Index          int               `json:"index"`
+	Active         bool              `json:"active"`
+	CurrentPath    string            `json:"current_path"`
+	CurrentCommand string            `json:"current_command"`
+	Env            map[string]string `json:"env"`
+	BufferFile     string            `json:"buffer_file"` // path relative to the snapshot dir, set by Save
+	buffer         string            `json:"-"`           // captured scrollback, held until Save writes it out
+}
+
+// Window is the captured state of a single tmux.Window.
+type Window struct {
+	Name   string `json:"name"`
+	Index  int    `json:"index"`
+	Layout string `json:"layout"` // raw `#{window_layout}` string, not a preset name
+	Panes  []Pane `json:"panes"`
+}
+
+// Session is the captured state of a single tmux session.
+type Session struct {
+	Name    string   `json:"name"`
+	Windows []Window `json:"windows"`
+}
+
+// Snapshot is the full captured state of a tmux server.
+type Snapshot struct {
+	Sessions []Session `json:"sessions"`
+}
+
+// Capture walks every session on server and records its windows, panes and
+// scrollback (up to historyLines per pane, or DefaultHistoryLines if <= 0).
+func Capture(server *tmux.Server, historyLines int) (*Snapshot, error) {
+	if historyLines <= 0 {
+		historyLines = DefaultHistoryLines
+	}
+
+	sessions, err := server.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &Snapshot{}
+
+	for _, s := range sessions {
+		windows, err := s.ListWindows()
+		if err != nil {
+			return nil, fmt.Errorf("session %s: %w", s.Name, err)
+		}
+
+		session := Session{Name: s.Name}
+
+		for _, w := range windows {
+			layout, err := windowLayout(w)
+			if err != nil {
+				return nil, fmt.Errorf("window %s: %w", w.Name, err)
+			}
+
+			panes, err := w.ListPanes()
+			if err != nil {
+				return nil, fmt.Errorf("window %s: %w", w.Name, err)
+			}
+
+			window := Window{Name: w.Name, Index: w.Id, Layout: layout}
+
+			for _, p := range panes {
+				pane, err := capturePane(p, historyLines)
+				if err != nil {
+					return nil, fmt.Errorf("pane %d: %w", p.Index, err)
+				}
+				window.Panes = append(window.Panes, pane)
+			}
+
+			session.Windows = append(session.Windows, window)
+		}
+
+		snap.Sessions = append(snap.Sessions, session)
+	}
+
+	return snap, nil
+}
+
+func windowLayout(w tmux.Window) (string, error) {
+	args := []string{"display-message", "-p", "-t", fmt.Sprintf("@%d", w.Id), "#{window_layout}"}
+	out, _, err := tmux.RunCmd(args)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(out, "\n"), nil
+}
+
+func capturePane(p tmux.Pane, historyLines int) (Pane, error) {
+	target := fmt.Sprintf("%s:%s.%d", p.SessionName, p.WindowName, p.Index)
+
+	currentPath, _, err := tmux.RunCmd([]string{"display-message", "-p", "-t", target, "#{pane_current_path}"})
+	if err != nil {
+		return Pane{}, err
+	}
+
+	currentCommand, _, err := tmux.RunCmd([]string{"display-message", "-p", "-t", target, "#{pane_current_command}"})
+	if err != nil {
+		return Pane{}, err
+	}
+
+	env, err := paneEnv(target)
+	if err != nil {
+		return Pane{}, err
+	}
+
+	buffer, _, err := tmux.RunCmd([]string{"capture-pane", "-p", "-J", "-S", fmt.Sprintf("-%d", historyLines), "-t", target})
+	if err != nil {
+		return Pane{}, err
+	}
+
+	return Pane{
+		Index:          p.Index,
+		Active:         p.Active,
+		CurrentPath:    strings.TrimRight(currentPath, "\n"),
+		CurrentCommand: strings.TrimRight(currentCommand, "\n"),
+		Env:            env,
+		buffer:         buffer,
+	}, nil
+}
+
+func paneEnv(target string) (map[string]string, error) {
+	out, _, err := tmux.RunCmd([]string{"show-environment", "-t", target})
+	if err != nil {
+		return nil, err
+	}
+
+	return parseEnv(out), nil
+}
+
+// parseEnv parses the `show-environment` output format: one `KEY=value`
+// per line, with lines starting with `-` marking unset variables (which
+// carry no value and are skipped).
+func parseEnv(out string) map[string]string {
+	env := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		if line == "" || strings.HasPrefix(line, "-") {
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			env[key] = value
+		}
+	}
+
+	return env
+}
+
+// Save serialises snap to dir: metadata as JSON plus one text file per pane
+// holding its captured scrollback. dir is created if it doesn't exist.
+func (snap *Snapshot) Save(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for si, s := range snap.Sessions {
+		for wi, w := range s.Windows {
+			for pi, p := range w.Panes {
+				name := fmt.Sprintf("%s-%d-%d.pane", sanitize(s.Name), w.Index, p.Index)
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(p.buffer), 0o644); err != nil {
+					return err
+				}
+				snap.Sessions[si].Windows[wi].Panes[pi].BufferFile = name
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, metadataFile), data, 0o644)
+}
+
+// Load reads back a Snapshot previously written with Save.
+func Load(dir string) (*Snapshot, error) {
+	data, err := os.ReadFile(filepath.Join(dir, metadataFile))
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &Snapshot{}
+	if err := json.Unmarshal(data, snap); err != nil {
+		return nil, err
+	}
+
+	return snap, nil
+}
+
+func sanitize(name string) string {
+	return strings.NewReplacer(":", "_", "/", "_", " ", "_").Replace(name)
+}
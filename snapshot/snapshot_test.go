@@ -0,0 +1,140 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain name is untouched", in: "work", want: "work"},
+		{name: "colon", in: "my:session", want: "my_session"},
+		{name: "slash", in: "a/b", want: "a_b"},
+		{name: "space", in: "my session", want: "my_session"},
+		{name: "all of the above", in: "a b:c/d", want: "a_b_c_d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitize(tt.in); got != tt.want {
+				t.Errorf("sanitize(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want map[string]string
+	}{
+		{
+			name: "simple vars",
+			out:  "FOO=bar\nBAZ=qux",
+			want: map[string]string{"FOO": "bar", "BAZ": "qux"},
+		},
+		{
+			name: "unset vars are skipped",
+			out:  "FOO=bar\n-UNSET_VAR",
+			want: map[string]string{"FOO": "bar"},
+		},
+		{
+			name: "value containing an equals sign",
+			out:  "URL=http://host/a=b",
+			want: map[string]string{"URL": "http://host/a=b"},
+		},
+		{
+			name: "blank lines are ignored",
+			out:  "FOO=bar\n\nBAZ=qux\n",
+			want: map[string]string{"FOO": "bar", "BAZ": "qux"},
+		},
+		{
+			name: "empty input",
+			out:  "",
+			want: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseEnv(tt.out); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseEnv(%q) = %v, want %v", tt.out, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	snap := &Snapshot{
+		Sessions: []Session{
+			{
+				Name: "my:session",
+				Windows: []Window{
+					{
+						Name:   "editor",
+						Index:  1,
+						Layout: "a1b2,80x24,0,0,0",
+						Panes: []Pane{
+							{
+								Index:          0,
+								Active:         true,
+								CurrentPath:    "/home/user/project",
+								CurrentCommand: "vim",
+								Env:            map[string]string{"FOO": "bar"},
+								buffer:         "line one\nline two\n",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	if err := snap.Save(dir); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if len(loaded.Sessions) != 1 || len(loaded.Sessions[0].Windows) != 1 || len(loaded.Sessions[0].Windows[0].Panes) != 1 {
+		t.Fatalf("Load() shape mismatch: %+v", loaded)
+	}
+
+	gotPane := loaded.Sessions[0].Windows[0].Panes[0]
+	wantPane := snap.Sessions[0].Windows[0].Panes[0]
+
+	if gotPane.CurrentPath != wantPane.CurrentPath {
+		t.Errorf("CurrentPath = %q, want %q", gotPane.CurrentPath, wantPane.CurrentPath)
+	}
+	if gotPane.CurrentCommand != wantPane.CurrentCommand {
+		t.Errorf("CurrentCommand = %q, want %q", gotPane.CurrentCommand, wantPane.CurrentCommand)
+	}
+	if !reflect.DeepEqual(gotPane.Env, wantPane.Env) {
+		t.Errorf("Env = %v, want %v", gotPane.Env, wantPane.Env)
+	}
+	if gotPane.BufferFile == "" {
+		t.Fatalf("BufferFile was not set by Save()")
+	}
+
+	contents, err := os.ReadFile(filepath.Join(dir, gotPane.BufferFile))
+	if err != nil {
+		t.Fatalf("reading buffer file: %v", err)
+	}
+	if string(contents) != "line one\nline two\n" {
+		t.Errorf("buffer file contents = %q, want %q", contents, "line one\nline two\n")
+	}
+}
@@ -0,0 +1,37 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import "fmt"
+
+// Target returns the canonical tmux target string for this pane: its
+// stable `%<pane_id>` when known, falling back to the name-based
+// `session:window.index` form otherwise. Prefer this over building target
+// strings by hand, since names containing `:`, `.` or spaces break the
+// name-based form silently.
+func (p *Pane) Target() string {
+	if p.ID != 0 {
+		return fmt.Sprintf("%%%d", p.ID)
+	}
+	return fmt.Sprintf("%s:%s.%d", p.SessionName, p.WindowName, p.Index)
+}
+
+// Target returns the canonical tmux target string for this window: its
+// stable `@<window_id>` when known, falling back to the name-based
+// `session:window` form otherwise.
+func (w *Window) Target() string {
+	if w.Id != 0 {
+		return fmt.Sprintf("@%d", w.Id)
+	}
+	return fmt.Sprintf("%s:%s", w.SessionName, w.Name)
+}
+
+// Target returns the canonical tmux target string for this session: its
+// stable `$<session_id>` when known, falling back to its name otherwise.
+func (s *Session) Target() string {
+	if s.Id != 0 {
+		return fmt.Sprintf("$%d", s.Id)
+	}
+	return s.Name
+}
@@ -0,0 +1,92 @@
+// The MIT License (MIT)
+// Copyright (C) 2019-2023 Georgiy Komarov <jubnzv@gmail.com>
+
+package tmux
+
+import "testing"
+
+func TestPaneTarget(t *testing.T) {
+	tests := []struct {
+		name string
+		pane Pane
+		want string
+	}{
+		{
+			name: "prefers stable pane id",
+			pane: Pane{ID: 3, SessionName: "s", WindowName: "w", Index: 1},
+			want: "%3",
+		},
+		{
+			name: "falls back to name-based target without an id",
+			pane: Pane{SessionName: "s", WindowName: "w", Index: 1},
+			want: "s:w.1",
+		},
+		{
+			name: "id target avoids names with reserved characters",
+			pane: Pane{ID: 7, SessionName: "my:session", WindowName: "win dow.1", Index: 2},
+			want: "%7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.pane.Target(); got != tt.want {
+				t.Errorf("Target() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWindowTarget(t *testing.T) {
+	tests := []struct {
+		name   string
+		window Window
+		want   string
+	}{
+		{
+			name:   "prefers stable window id",
+			window: Window{Id: 5, SessionName: "s", Name: "w"},
+			want:   "@5",
+		},
+		{
+			name:   "falls back to name-based target without an id",
+			window: Window{SessionName: "s", Name: "w"},
+			want:   "s:w",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.Target(); got != tt.want {
+				t.Errorf("Target() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionTarget(t *testing.T) {
+	tests := []struct {
+		name    string
+		session Session
+		want    string
+	}{
+		{
+			name:    "prefers stable session id",
+			session: Session{Id: 2, Name: "my session"},
+			want:    "$2",
+		},
+		{
+			name:    "falls back to name without an id",
+			session: Session{Name: "my session"},
+			want:    "my session",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.session.Target(); got != tt.want {
+				t.Errorf("Target() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ package tmux
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -26,6 +27,12 @@ type Window struct {
 	StartDirectory string // Path to window working directory
 	Layout         string // Preset arrangements of panes
 	Panes          []Pane // List of panes used in initial window configuration
+
+	// PreSplitCommand runs once in the window's initial pane, before
+	// Configuration.Apply splits off the rest of Panes. Useful for setup
+	// (e.g. exporting env vars) that every pane in the window should
+	// inherit.
+	PreSplitCommand string
 }
 
 // Creates a new window object.
@@ -65,7 +72,7 @@ func (w *Window) Select() error {
 	args := []string{
 		"select-window",
 		"-t",
-		fmt.Sprintf("@%d", w.Id),
+		w.Target(),
 	}
 	_, stdErr, err := RunCmd(args)
 	if err != nil {
@@ -74,13 +81,68 @@ func (w *Window) Select() error {
 	return nil
 }
 
-// Creates a pane inside this window.
-func (w *Window) SplitPane() (pane Pane, err error) {
-	args := []string{
-		"split-window",
-		"-t", fmt.Sprintf("%s:%s", w.SessionName, w.Name),
-		"-c", w.StartDirectory,
-		"-F", "#{pane_id}"}
+// SplitPaneOpts controls how Window.SplitPane divides up an existing pane.
+// A zero value behaves like a bare `split-window`: a 50/50 vertical split
+// of the window's active pane.
+type SplitPaneOpts struct {
+	Horizontal bool   // -h: split to the left/right instead of above/below
+	Percent    int    // -p <percent>: new pane size as a percentage (0 = unset)
+	Size       int    // -l <size>: new pane size in lines/columns (0 = unset, ignored if Percent is set)
+	Before     bool   // -b: new pane goes before the target pane instead of after
+	Full       bool   // -f: new pane spans the full window height/width
+	TargetPane string // -t: pane to split from; empty uses the window's Target()
+	Detached   bool   // -d: don't change the window's active pane
+}
+
+// Creates a pane inside this window. opts may be nil for the previous
+// behaviour (50/50 vertical split of the active pane).
+func (w *Window) SplitPane(opts *SplitPaneOpts) (pane Pane, err error) {
+	target := w.Target()
+	args := []string{"split-window"}
+
+	if opts != nil {
+		if opts.Horizontal {
+			args = append(args, "-h")
+		} else {
+			args = append(args, "-v")
+		}
+		if opts.Before {
+			args = append(args, "-b")
+		}
+		if opts.Full {
+			args = append(args, "-f")
+		}
+		if opts.Detached {
+			args = append(args, "-d")
+		}
+
+		switch {
+		case opts.Percent > 0:
+			args = append(args, "-p", strconv.Itoa(opts.Percent))
+		case opts.Size > 0:
+			args = append(args, "-l", strconv.Itoa(opts.Size))
+		}
+
+		if opts.TargetPane != "" {
+			target = opts.TargetPane
+		}
+	}
+
+	args = append(args, "-t", target, "-c", w.StartDirectory, "-F", "#{pane_id}")
+
+	// Snapshot the existing panes by ID before splitting. With Before (-b)
+	// the new pane takes the target's old index and every later pane
+	// shifts, so "the new pane" is not reliably "the last one returned by
+	// ListPanes" -- diffing IDs instead of trusting position.
+	before, err := w.ListPanes()
+	if err != nil {
+		return pane, err
+	}
+	existed := make(map[int]bool, len(before))
+	for _, p := range before {
+		existed[p.ID] = true
+	}
+
 	_, err_out, err_exec := RunCmd(args)
 	if err_exec != nil {
 		// It's okay, if session already exists.
@@ -94,8 +156,16 @@ func (w *Window) SplitPane() (pane Pane, err error) {
 		return pane, err
 	}
 
+	newPane := panes[len(panes)-1]
+	for _, p := range panes {
+		if !existed[p.ID] {
+			newPane = p
+			break
+		}
+	}
+
 	// Append the Pane struct to the Panes slice in the Window struct.
-	w.Panes = append(w.Panes, panes[len(panes)-1])
+	w.Panes = append(w.Panes, newPane)
 
-	return panes[len(panes)-1], nil
+	return newPane, nil
 }